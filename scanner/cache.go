@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-memory Cache implementation. It is safe for concurrent use.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	result    Result
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]memoryCacheEntry{}}
+}
+
+// Get returns the cached result for domain, if one exists and hasn't expired.
+func (c *MemoryCache) Get(domain string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[domain]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return Result{}, false
+	}
+
+	return entry.result, true
+}
+
+// Set caches result for domain for the given ttl.
+func (c *MemoryCache) Set(domain string, result Result, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[domain] = memoryCacheEntry{result: result, expiresAt: time.Now().Add(ttl)}
+}
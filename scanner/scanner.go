@@ -0,0 +1,261 @@
+// Package scanner builds on security.Parser to fetch and parse security.txt
+// files for many domains concurrently, producing a structured report per
+// domain. It is meant as a foundation for security-posture dashboards and CI
+// checks that need to cover an organization's whole domain portfolio.
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asankov/security-txt-parser/security"
+)
+
+// Options configures a Scanner.
+type Options struct {
+	// Concurrency is the maximum number of domains scanned at once. Defaults to 10.
+	Concurrency int
+
+	// Timeout bounds the fetch of a single domain. Defaults to 10s.
+	Timeout time.Duration
+
+	// RespectRobots, when true, skips a domain's security.txt paths that its
+	// robots.txt disallows for User-agent: *.
+	RespectRobots bool
+
+	// HTTPClient is used to fetch robots.txt when RespectRobots is set.
+	// Defaults to http.DefaultClient.
+	//
+	// security.txt itself is always fetched through the parser passed to New,
+	// so its own HTTPClient, MaxBytes, AllowedContentTypes, RequireHTTPS and
+	// RedirectPolicy govern that fetch instead.
+	HTTPClient *http.Client
+
+	// Cache, when set, is consulted before fetching a domain and populated
+	// after, using the security.txt's Expires field as the TTL so re-scans
+	// are cheap.
+	Cache Cache
+}
+
+// TLSInfo is the subset of a TLS connection's state relevant to a security
+// posture report.
+type TLSInfo struct {
+	Version     uint16
+	CipherSuite uint16
+}
+
+// Result is the outcome of scanning a single domain.
+type Result struct {
+	Domain string
+
+	// URL is the security.txt URL that was successfully requested, if any,
+	// before redirects; TXT.FinalURL holds the URL after redirects.
+	URL string
+
+	TXT        *security.TXT
+	StatusCode int
+	TLS        *TLSInfo
+	Latency    time.Duration
+	Err        error
+	ScannedAt  time.Time
+}
+
+// Cache lets a Scanner avoid re-fetching a domain's security.txt before it expires.
+type Cache interface {
+	Get(domain string) (Result, bool)
+	Set(domain string, result Result, ttl time.Duration)
+}
+
+// Scanner fetches and parses security.txt files for a set of domains concurrently.
+type Scanner struct {
+	parser *security.Parser
+	opts   Options
+}
+
+// New creates a Scanner that uses parser to parse every domain's security.txt.
+func New(parser *security.Parser, opts Options) *Scanner {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	return &Scanner{parser: parser, opts: opts}
+}
+
+// Scan fetches and parses the security.txt file for every domain
+// concurrently, streaming a Result for each as soon as it is ready. The
+// returned channel is closed once every domain has been scanned or ctx is done.
+func (s *Scanner) Scan(ctx context.Context, domains []string) <-chan Result {
+	results := make(chan Result)
+
+	go func() {
+		defer close(results)
+
+		sem := make(chan struct{}, s.opts.Concurrency)
+
+		var wg sync.WaitGroup
+
+		for _, domain := range domains {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case sem <- struct{}{}:
+			}
+
+			wg.Add(1)
+
+			go func(domain string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				result := s.scanOne(ctx, domain)
+
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}(domain)
+		}
+
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// scanOne fetches and parses a single domain's security.txt, consulting and
+// populating the cache when one is configured.
+func (s *Scanner) scanOne(ctx context.Context, domain string) Result {
+	if s.opts.Cache != nil {
+		if cached, ok := s.opts.Cache.Get(domain); ok {
+			return cached
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := Result{Domain: domain, ScannedAt: start}
+
+	for _, path := range []string{".well-known/security.txt", "security.txt"} {
+		if s.opts.RespectRobots && !s.robotsAllow(ctx, domain, "/"+path) {
+			result.Err = fmt.Errorf("robots.txt disallows /%s on [%s]", path, domain)
+			continue
+		}
+
+		rawURL := "https://" + domain + "/" + path
+
+		txt, statusCode, tlsInfo, err := s.fetchAndParse(ctx, rawURL)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+
+		result.URL = rawURL
+		result.StatusCode = statusCode
+		result.TLS = tlsInfo
+		result.TXT = txt
+		result.Err = nil
+
+		break
+	}
+
+	result.Latency = time.Since(start)
+
+	if s.opts.Cache != nil && result.TXT != nil {
+		if ttl := time.Until(result.TXT.Expires); ttl > 0 {
+			s.opts.Cache.Set(domain, result, ttl)
+		}
+	}
+
+	return result
+}
+
+// fetchAndParse fetches rawURL and parses it as a security.txt file via the
+// parser's own FetchContext, so a scan gets the same MaxBytes,
+// AllowedContentTypes, RequireHTTPS and RedirectPolicy guarantees a single
+// ParseFromURLContext call would - a bulk scan of untrusted domains is
+// exactly the DoS surface those guarantees exist for.
+func (s *Scanner) fetchAndParse(ctx context.Context, rawURL string) (*security.TXT, int, *TLSInfo, error) {
+	txt, resp, err := s.parser.FetchContext(ctx, rawURL)
+
+	var (
+		statusCode int
+		tlsInfo    *TLSInfo
+	)
+
+	if resp != nil {
+		statusCode = resp.StatusCode
+
+		if resp.TLS != nil {
+			tlsInfo = &TLSInfo{Version: resp.TLS.Version, CipherSuite: resp.TLS.CipherSuite}
+		}
+	}
+
+	if err != nil {
+		return nil, statusCode, tlsInfo, err
+	}
+
+	return txt, statusCode, tlsInfo, nil
+}
+
+// robotsAllow reports whether domain's robots.txt permits fetching path for
+// User-agent: *. Domains without a robots.txt, or with one that can't be
+// fetched, are treated as allowing everything.
+func (s *Scanner) robotsAllow(ctx context.Context, domain, path string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+domain+"/robots.txt", nil)
+	if err != nil {
+		return true
+	}
+
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return true
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return true
+	}
+
+	var (
+		lineScanner = bufio.NewScanner(resp.Body)
+		appliesToUs bool
+		allowed     = true
+	)
+
+	for lineScanner.Scan() {
+		directive, value, ok := strings.Cut(strings.TrimSpace(lineScanner.Text()), ":")
+		if !ok {
+			continue
+		}
+
+		directive = strings.TrimSpace(directive)
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(directive) {
+		case "user-agent":
+			appliesToUs = value == "*"
+		case "disallow":
+			if appliesToUs && value != "" && strings.HasPrefix(path, value) {
+				allowed = false
+			}
+		}
+	}
+
+	return allowed
+}
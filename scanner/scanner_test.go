@@ -0,0 +1,147 @@
+package scanner_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/asankov/security-txt-parser/scanner"
+	"github.com/asankov/security-txt-parser/security"
+	"github.com/stretchr/testify/require"
+)
+
+func hostOf(t *testing.T, rawURL string) string {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+
+	return u.Host
+}
+
+func TestScanMultipleDomains(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/security.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte("Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"))
+	}))
+	t.Cleanup(srv.Close)
+
+	parser := security.NewParserWithOptions(security.ParserOptions{HTTPClient: srv.Client()})
+	s := scanner.New(parser, scanner.Options{HTTPClient: srv.Client()})
+
+	domains := []string{hostOf(t, srv.URL), hostOf(t, srv.URL), hostOf(t, srv.URL)}
+
+	results := s.Scan(context.Background(), domains)
+
+	count := 0
+	for result := range results {
+		require.NoError(t, result.Err)
+		require.NotNil(t, result.TXT)
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		count++
+	}
+
+	require.Equal(t, len(domains), count)
+}
+
+func TestScanRespectsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const concurrency = 2
+
+	var (
+		inFlight int32
+		maxSeen  int32
+	)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			seen := atomic.LoadInt32(&maxSeen)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxSeen, seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		w.Write([]byte("Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"))
+	}))
+	t.Cleanup(srv.Close)
+
+	parser := security.NewParserWithOptions(security.ParserOptions{HTTPClient: srv.Client()})
+	s := scanner.New(parser, scanner.Options{HTTPClient: srv.Client(), Concurrency: concurrency})
+
+	domains := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		domains = append(domains, hostOf(t, srv.URL))
+	}
+
+	for range s.Scan(context.Background(), domains) {
+	}
+
+	require.LessOrEqual(t, atomic.LoadInt32(&maxSeen), int32(concurrency))
+}
+
+func TestScanCachesResult(t *testing.T) {
+	t.Parallel()
+
+	var hits int32
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte("Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"))
+	}))
+	t.Cleanup(srv.Close)
+
+	parser := security.NewParserWithOptions(security.ParserOptions{HTTPClient: srv.Client()})
+	cache := scanner.NewMemoryCache()
+	s := scanner.New(parser, scanner.Options{HTTPClient: srv.Client(), Cache: cache})
+
+	domain := hostOf(t, srv.URL)
+
+	for i := 0; i < 2; i++ {
+		for result := range s.Scan(context.Background(), []string{domain}) {
+			require.NoError(t, result.Err)
+		}
+	}
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&hits), "the second scan should be served from cache")
+}
+
+func TestScanRespectsRobotsTxt(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /\n")
+		default:
+			w.Write([]byte("Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	parser := security.NewParserWithOptions(security.ParserOptions{HTTPClient: srv.Client()})
+	s := scanner.New(parser, scanner.Options{HTTPClient: srv.Client(), RespectRobots: true})
+
+	results := s.Scan(context.Background(), []string{hostOf(t, srv.URL)})
+
+	for result := range results {
+		require.Error(t, result.Err)
+		require.Nil(t, result.TXT)
+	}
+}
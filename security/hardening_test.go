@@ -0,0 +1,179 @@
+package security_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/security-txt-parser/security"
+	"github.com/stretchr/testify/require"
+)
+
+const validBody = "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"
+
+func TestRedirectPolicy(t *testing.T) {
+	t.Parallel()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(validBody))
+	}))
+	t.Cleanup(target.Close)
+
+	sameHostRedirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirected" {
+			w.Write([]byte(validBody))
+			return
+		}
+
+		http.Redirect(w, r, "/redirected", http.StatusFound)
+	}))
+	t.Cleanup(sameHostRedirect.Close)
+
+	crossHostRedirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	t.Cleanup(crossHostRedirect.Close)
+
+	testCases := []struct {
+		name    string
+		policy  security.RedirectPolicy
+		url     string
+		wantErr bool
+	}{
+		{name: "follow same host", policy: security.RedirectFollow, url: sameHostRedirect.URL, wantErr: false},
+		{name: "follow cross host", policy: security.RedirectFollow, url: crossHostRedirect.URL, wantErr: false},
+		{name: "follow-same-host allows same host", policy: security.RedirectFollowSameHost, url: sameHostRedirect.URL, wantErr: false},
+		{name: "follow-same-host rejects cross host", policy: security.RedirectFollowSameHost, url: crossHostRedirect.URL, wantErr: true},
+		{name: "reject rejects any redirect", policy: security.RedirectReject, url: sameHostRedirect.URL, wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := security.NewParserWithOptions(security.ParserOptions{RedirectPolicy: testCase.policy})
+
+			txt, err := p.ParseFromURL(testCase.url)
+
+			if testCase.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, txt)
+		})
+	}
+}
+
+func TestRequireHTTPS(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(validBody))
+	}))
+	t.Cleanup(srv.Close)
+
+	require.True(t, strings.HasPrefix(srv.URL, "http://"))
+
+	p := security.NewParserWithOptions(security.ParserOptions{RequireHTTPS: true})
+
+	_, err := p.ParseFromURL(srv.URL + "/security.txt")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, security.ErrInsecureScheme))
+}
+
+func TestMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(validBody + strings.Repeat("#", 100)))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := security.NewParserWithOptions(security.ParserOptions{MaxBytes: int64(len(validBody))})
+
+	_, err := p.ParseFromURL(srv.URL + "/security.txt")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, security.ErrResponseTooLarge))
+}
+
+func TestAllowedContentTypes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(validBody))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := security.NewParserWithOptions(security.ParserOptions{})
+
+	_, err := p.ParseFromURL(srv.URL + "/security.txt")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, security.ErrDisallowedContentType))
+
+	p = security.NewParserWithOptions(security.ParserOptions{AllowedContentTypes: []string{"application/json"}})
+
+	txt, err := p.ParseFromURL(srv.URL + "/security.txt")
+	require.NoError(t, err)
+	require.NotNil(t, txt)
+}
+
+func TestRetryAfterIsCapped(t *testing.T) {
+	t.Parallel()
+
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.Write([]byte(validBody))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := security.NewParserWithOptions(security.ParserOptions{MaxRetryAfter: 50 * time.Millisecond})
+
+	start := time.Now()
+	txt, err := p.ParseFromURL(srv.URL + "/security.txt")
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	require.NotNil(t, txt)
+	require.Less(t, elapsed, time.Second, "the wait must be capped at MaxRetryAfter, not the server-supplied Retry-After")
+}
+
+func TestParseFromURLContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.Write([]byte(validBody))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := security.NewParserWithOptions(security.ParserOptions{MaxRetryAfter: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := p.ParseFromURLContext(ctx, srv.URL+"/security.txt")
+	require.Error(t, err)
+}
@@ -0,0 +1,364 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/openpgp/clearsign"
+	"golang.org/x/text/language"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity string
+
+const (
+	// SeverityError marks a diagnostic that violates RFC9116.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a diagnostic that doesn't make parsing fail, such as
+	// a likely mistake, a stale Expires, or a Contact web URI that isn't https://.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic describes a single issue found while linting a security.txt file.
+type Diagnostic struct {
+	// Line is the 1-indexed line the issue was found on, or 0 when it applies to the file as a whole.
+	Line int
+
+	// Field is the name of the field the issue relates to, empty when not field-specific.
+	Field string
+
+	Severity Severity
+	Message  string
+}
+
+// Report is the result of linting a security.txt file with Lint.
+type Report struct {
+	Diagnostics []Diagnostic
+}
+
+// HasErrors reports whether the report contains any SeverityError diagnostics.
+func (r *Report) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lint parses in like Parse, but never aborts on the first problem. It
+// returns every field it could read plus a Report carrying every issue it
+// found, each with the line number it occurred on. The returned error is the
+// same combined go-multierror that a Lenient Parse would return.
+//
+// Because Lint has no way to know the path the file was served from, it
+// cannot flag a missing Canonical field for a non-well-known path the way
+// LintFromURLContext can - use that instead when linting a fetched file.
+func (p *Parser) Lint(in io.Reader) (*TXT, *Report, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while reading file: %w", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), clearsignHeader) {
+		block, _ := clearsign.Decode(data)
+		if block == nil {
+			return nil, nil, ErrMalformedSignature
+		}
+
+		data = block.Plaintext
+	}
+
+	txt, diagnostics, err := p.lintBody(data, "")
+
+	return txt, &Report{Diagnostics: diagnostics}, err
+}
+
+// LintFromURL fetches and lints the security.txt file at rawURL, equivalent
+// to LintFromURLContext with context.Background().
+func (p *Parser) LintFromURL(rawURL string) (*TXT, *Report, error) {
+	return p.LintFromURLContext(context.Background(), rawURL)
+}
+
+// LintFromURLContext fetches the security.txt file at rawURL through the same
+// hardened path ParseFromURLContext uses - RequireHTTPS, RedirectPolicy,
+// Retry-After handling, Content-Type checking and MaxBytes - then lints it
+// like Lint. Knowing the URL the file was served from lets it additionally
+// flag a missing Canonical field when rawURL isn't the well-known location
+// RFC9116 section 3 recommends.
+func (p *Parser) LintFromURLContext(ctx context.Context, rawURL string) (*TXT, *Report, error) {
+	body, finalURL, _, err := p.fetchBody(ctx, rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(body), clearsignHeader) {
+		block, _ := clearsign.Decode(body)
+		if block == nil {
+			return nil, nil, ErrMalformedSignature
+		}
+
+		body = block.Plaintext
+	}
+
+	txt, diagnostics, err := p.lintBody(body, finalURL)
+
+	if txt != nil {
+		txt.RetrievedFrom = rawURL
+		txt.FinalURL = finalURL
+	}
+
+	return txt, &Report{Diagnostics: diagnostics}, err
+}
+
+// lintBody runs parseFields in lenient mode and, when sourceURL is known,
+// appends diagnostics that depend on where the file was served from.
+func (p *Parser) lintBody(data []byte, sourceURL string) (*TXT, []Diagnostic, error) {
+	txt, diagnostics, err := p.parseFields(data, true, p.strictUnknownFields)
+	if txt != nil {
+		diagnostics = append(diagnostics, canonicalDiagnostic(txt, sourceURL)...)
+	}
+
+	return txt, diagnostics, err
+}
+
+// canonicalDiagnostic warns when a file was retrieved from somewhere other
+// than the well-known security.txt location but doesn't declare a Canonical
+// URI, as RFC9116 section 3 recommends so other retrieval paths can be
+// recognized as authoritative.
+func canonicalDiagnostic(txt *TXT, sourceURL string) []Diagnostic {
+	if sourceURL == "" || len(txt.Canonical) > 0 {
+		return nil
+	}
+
+	u, err := url.Parse(sourceURL)
+	if err != nil || strings.HasSuffix(u.Path, "/.well-known/security.txt") {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Field:    "Canonical",
+		Severity: SeverityWarning,
+		Message:  "security.txt was served from a non-well-known path but doesn't declare a Canonical URI, as RFC9116 section 3 recommends",
+	}}
+}
+
+// extensionFieldPattern matches the generic `field-name ":" FWS unstructured`
+// grammar RFC9116 section 2.4 permits for IANA-registered or experimental
+// ("x-"-prefixed) extension fields this package doesn't model directly.
+var extensionFieldPattern = regexp.MustCompile(`^([!-9;-~]+):[ \t]*(.*)$`)
+
+// parseFields parses the field lines of a security.txt body - the clearsign
+// wrapper, if any, has already been stripped by the caller. When lenient is
+// false it returns on the first problem with the same sentinel errors Parse
+// has always returned; when true it keeps going, collecting every problem
+// into diagnostics and into the returned go-multierror.
+//
+//nolint:funlen,gocognit,cyclop
+func (p *Parser) parseFields(data []byte, lenient, strictUnknownFields bool) (*TXT, []Diagnostic, error) {
+	var (
+		txt         TXT
+		diagnostics []Diagnostic
+		multiErr    *multierror.Error
+		scanner     = bufio.NewScanner(bytes.NewReader(data))
+		lineNo      int
+		expiresSeen bool
+	)
+
+	fail := func(line int, field string, sentinel error) error {
+		diagnostics = append(diagnostics, Diagnostic{Line: line, Field: field, Severity: SeverityError, Message: sentinel.Error()})
+
+		if !lenient {
+			return sentinel
+		}
+
+		multiErr = multierror.Append(multiErr, sentinel)
+
+		return nil
+	}
+
+	warn := func(line int, field, message string) {
+		diagnostics = append(diagnostics, Diagnostic{Line: line, Field: field, Severity: SeverityWarning, Message: message})
+	}
+
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, commentPrefix) || line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, acknowledgmentsPrefix) {
+			value := trimFieldValue(line, acknowledgmentsPrefix)
+
+			txt.Acknowledgments = append(txt.Acknowledgments, value)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, canonicalPrefix) {
+			value := trimFieldValue(line, canonicalPrefix)
+
+			txt.Canonical = append(txt.Canonical, value)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, contactPrefix) {
+			value := trimFieldValue(line, contactPrefix)
+
+			if message := lintContact(value); message != "" {
+				warn(lineNo, "Contact", message)
+			}
+
+			txt.Contact = append(txt.Contact, value)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, encryptionPrefix) {
+			txt.Encryption = trimFieldValue(line, encryptionPrefix)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, hiringPrefix) {
+			txt.Hiring = trimFieldValue(line, hiringPrefix)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, expiresPrefix) {
+			if expiresSeen {
+				if err := fail(lineNo, "Expires", ErrExpiresMustBePresentOnlyOnce); err != nil {
+					return nil, diagnostics, err
+				}
+
+				continue
+			}
+
+			expiresSeen = true
+
+			value := trimFieldValue(line, expiresPrefix)
+
+			expires, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				if err := fail(lineNo, "Expires", ErrExpiresNotAValidRFC3339Date); err != nil {
+					return nil, diagnostics, err
+				}
+
+				continue
+			}
+
+			if expires.Before(time.Now()) {
+				warn(lineNo, "Expires", "Expires is in the past; this file is stale")
+			}
+
+			txt.Expires = expires
+
+			continue
+		}
+
+		if strings.HasPrefix(line, policyPrefix) {
+			txt.Policy = trimFieldValue(line, policyPrefix)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, preferredLanguagesPrefix) {
+			if len(txt.PreferredLanguages) != 0 {
+				if err := fail(lineNo, "Preferred-Languages", ErrPreferredLanguagesMustBePresentOnlyOnce); err != nil {
+					return nil, diagnostics, err
+				}
+
+				continue
+			}
+
+			value := trimFieldValue(line, preferredLanguagesPrefix)
+
+			for _, tag := range strings.Split(value, ",") {
+				tag = strings.Trim(tag, " ")
+
+				if _, err := language.Parse(tag); err != nil {
+					warn(lineNo, "Preferred-Languages", fmt.Sprintf("%q is not a valid RFC5646 language tag: %s", tag, err))
+				}
+
+				txt.PreferredLanguages = append(txt.PreferredLanguages, tag)
+			}
+
+			continue
+		}
+
+		if !strictUnknownFields {
+			if m := extensionFieldPattern.FindStringSubmatch(line); m != nil {
+				name := strings.ToLower(m[1])
+
+				if txt.Extensions == nil {
+					txt.Extensions = map[string][]string{}
+				}
+
+				txt.Extensions[name] = append(txt.Extensions[name], strings.TrimSpace(m[2]))
+
+				continue
+			}
+		}
+
+		if err := fail(lineNo, "", &UnknownSymbolError{Line: line}); err != nil {
+			return nil, diagnostics, err
+		}
+	}
+
+	if len(txt.Contact) == 0 {
+		if err := fail(0, "Contact", ErrContactMustBePresent); err != nil {
+			return nil, diagnostics, err
+		}
+	}
+
+	if !expiresSeen {
+		if err := fail(0, "Expires", ErrExpiresMustBePresent); err != nil {
+			return nil, diagnostics, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, diagnostics, fmt.Errorf("error while reading file: %w", err)
+	}
+
+	return &txt, diagnostics, multiErr.ErrorOrNil()
+}
+
+func trimFieldValue(line, prefix string) string {
+	return strings.Trim(strings.TrimPrefix(line, prefix), " ")
+}
+
+// lintContact returns a warning message when value doesn't follow RFC9116's
+// expectations for a Contact URI, or an empty string when it looks fine.
+func lintContact(value string) string {
+	switch {
+	case strings.HasPrefix(value, "mailto:"):
+		addr, err := mail.ParseAddress(strings.TrimPrefix(value, "mailto:"))
+		if err != nil {
+			return fmt.Sprintf("Contact %q is not a valid mailto: address: %s", value, err)
+		}
+
+		if local, _, ok := strings.Cut(addr.Address, "@"); ok && !strings.EqualFold(local, "security") {
+			return fmt.Sprintf("Contact %q doesn't use the \"security\" role mailbox RFC2142 recommends for security contacts", value)
+		}
+	case strings.HasPrefix(value, "http://"):
+		return fmt.Sprintf("Contact %q is a web URI but doesn't use https://, as RFC9116 requires", value)
+	}
+
+	return ""
+}
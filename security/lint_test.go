@@ -0,0 +1,187 @@
+package security_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/asankov/security-txt-parser/security"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintExpiresMalformedProducesOneDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	body := "Contact: mailto:security@example.com\nExpires: not-a-date\n"
+
+	p := security.NewParser()
+
+	txt, report, err := p.Lint(strings.NewReader(body))
+	require.NotNil(t, txt)
+	require.Error(t, err)
+
+	var expiresDiagnostics []security.Diagnostic
+	for _, d := range report.Diagnostics {
+		if d.Field == "Expires" {
+			expiresDiagnostics = append(expiresDiagnostics, d)
+		}
+	}
+
+	require.Len(t, expiresDiagnostics, 1, "a malformed Expires line should produce exactly one diagnostic, not a spurious second one about it being absent")
+	require.Equal(t, security.ErrExpiresNotAValidRFC3339Date.Error(), expiresDiagnostics[0].Message)
+}
+
+func TestLintDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		body      string
+		wantField string
+	}{
+		{
+			name:      "missing Contact",
+			body:      "Expires: 2099-01-01T00:00:00Z\n",
+			wantField: "Contact",
+		},
+		{
+			name:      "missing Expires",
+			body:      "Contact: mailto:security@example.com\n",
+			wantField: "Expires",
+		},
+		{
+			name:      "Expires present twice",
+			body:      "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\nExpires: 2099-01-02T00:00:00Z\n",
+			wantField: "Expires",
+		},
+		{
+			name:      "unknown field",
+			body:      "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\nSHOULD FAIL HERE.\n",
+			wantField: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := security.NewParser()
+
+			_, report, err := p.Lint(strings.NewReader(testCase.body))
+			require.Error(t, err)
+			require.NotEmpty(t, report.Diagnostics)
+			require.True(t, report.HasErrors())
+
+			found := false
+			for _, d := range report.Diagnostics {
+				if d.Field == testCase.wantField && d.Severity == security.SeverityError {
+					found = true
+				}
+			}
+
+			require.True(t, found, "expected an error diagnostic for field %q, got %+v", testCase.wantField, report.Diagnostics)
+		})
+	}
+}
+
+func TestLintExtensionFields(t *testing.T) {
+	t.Parallel()
+
+	body := "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\nX-Custom-Field: hello\nX-Custom-Field: world\n"
+
+	p := security.NewParser()
+
+	txt, report, err := p.Lint(strings.NewReader(body))
+	require.NoError(t, err)
+	require.False(t, report.HasErrors())
+
+	require.Equal(t, []string{"hello", "world"}, txt.Extensions["x-custom-field"])
+}
+
+func TestLintExtensionFieldsRejectedWhenStrict(t *testing.T) {
+	t.Parallel()
+
+	body := "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\nX-Custom-Field: hello\n"
+
+	p := security.NewParserWithOptions(security.ParserOptions{StrictUnknownFields: true})
+
+	_, report, err := p.Lint(strings.NewReader(body))
+	require.Error(t, err)
+	require.True(t, report.HasErrors())
+}
+
+func TestLintContactMailboxConvention(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		contact  string
+		wantWarn bool
+	}{
+		{name: "security mailbox follows RFC2142", contact: "mailto:security@example.com"},
+		{name: "non-security mailbox warns", contact: "mailto:bob@example.com", wantWarn: true},
+		{name: "https web URI is fine", contact: "https://example.com/vulnz"},
+		{name: "http web URI warns about scheme", contact: "http://example.com/vulnz", wantWarn: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := "Contact: " + testCase.contact + "\nExpires: 2099-01-01T00:00:00Z\n"
+
+			p := security.NewParser()
+
+			_, report, err := p.Lint(strings.NewReader(body))
+			require.NoError(t, err)
+
+			warned := false
+			for _, d := range report.Diagnostics {
+				if d.Field == "Contact" && d.Severity == security.SeverityWarning {
+					warned = true
+				}
+			}
+
+			require.Equal(t, testCase.wantWarn, warned, "diagnostics: %+v", report.Diagnostics)
+		})
+	}
+}
+
+func TestLintFromURLContextFlagsMissingCanonical(t *testing.T) {
+	t.Parallel()
+
+	body := "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	p := security.NewParser()
+
+	txt, report, err := p.LintFromURL(srv.URL + "/security.txt")
+	require.NoError(t, err)
+	require.NotNil(t, txt)
+
+	found := false
+	for _, d := range report.Diagnostics {
+		if d.Field == "Canonical" && d.Severity == security.SeverityWarning {
+			found = true
+		}
+	}
+
+	require.True(t, found, "expected a Canonical warning when served from a non-well-known path, got %+v", report.Diagnostics)
+
+	txt, report, err = p.LintFromURL(srv.URL + "/.well-known/security.txt")
+	require.NoError(t, err)
+	require.NotNil(t, txt)
+
+	for _, d := range report.Diagnostics {
+		require.NotEqual(t, "Canonical", d.Field, "well-known path should not be flagged")
+	}
+}
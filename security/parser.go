@@ -1,17 +1,45 @@
 package security
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+var clearsignHeader = []byte("-----BEGIN PGP SIGNED MESSAGE-----")
+
+// defaultMaxBytes is the default cap on how much of a fetched security.txt body is read.
+const defaultMaxBytes = 128 * 1024
+
+// defaultMaxRetryAfter caps how long getWithRetry waits on a server-supplied
+// Retry-After when ParserOptions.MaxRetryAfter is not set.
+const defaultMaxRetryAfter = 30 * time.Second
+
+var defaultAllowedContentTypes = []string{"text/plain"}
+
+// RedirectPolicy controls how Parser.ParseFromURL follows HTTP redirects.
+type RedirectPolicy int
+
+const (
+	// RedirectFollow follows any redirect the server returns. This is the default.
+	RedirectFollow RedirectPolicy = iota
+	// RedirectFollowSameHost follows a redirect only if it stays on the same host.
+	RedirectFollowSameHost
+	// RedirectReject treats any redirect response as an error.
+	RedirectReject
 )
 
 var (
@@ -42,6 +70,22 @@ var (
 	ErrExpiresMustBePresent = fmt.Errorf("Expires must be present") //nolint:stylecheck
 	// ErrExpiresNotAValidRFC3339Date is returned when the Expires field is not a valid RFC3339 date.
 	ErrExpiresNotAValidRFC3339Date = fmt.Errorf("Expires is not a valid RFC3339 date") //nolint:stylecheck
+	// ErrMalformedSignature is returned when the input looks clearsigned but the OpenPGP armor could not be decoded.
+	ErrMalformedSignature = fmt.Errorf("malformed OpenPGP clearsign block")
+	// ErrSignatureRequired is returned by StrictSignature parsers when the input is not clearsigned.
+	ErrSignatureRequired = fmt.Errorf("security.txt is not signed, but StrictSignature is enabled")
+	// ErrSignatureNotVerified is returned by StrictSignature parsers when the signature could not be verified.
+	ErrSignatureNotVerified = fmt.Errorf("signature could not be verified")
+	// ErrNoKeyringConfigured is returned when a file is signed but no keyring was configured to verify it against.
+	ErrNoKeyringConfigured = fmt.Errorf("no keyring configured to verify the signature")
+	// ErrResponseTooLarge is returned when a fetched security.txt body exceeds ParserOptions.MaxBytes.
+	ErrResponseTooLarge = fmt.Errorf("security.txt response exceeds the configured MaxBytes")
+	// ErrInsecureScheme is returned when RequireHTTPS is set and a URL (or a redirect target) isn't https://.
+	ErrInsecureScheme = fmt.Errorf("RequireHTTPS is enabled but the URL is not https://")
+	// ErrDisallowedContentType is returned when a response's Content-Type isn't in AllowedContentTypes.
+	ErrDisallowedContentType = fmt.Errorf("response Content-Type is not allowed")
+	// ErrCrossHostRedirect is returned when RedirectPolicy is RedirectFollowSameHost and a redirect changes host.
+	ErrCrossHostRedirect = fmt.Errorf("redirected to a different host")
 )
 
 var (
@@ -53,6 +97,8 @@ var (
 	Parse = defaultParser.Parse
 
 	ParseFromURL = defaultParser.ParseFromURL
+
+	ParseFromURLContext = defaultParser.ParseFromURLContext
 )
 
 // Parser is a struct that parses the security.txt file.
@@ -61,11 +107,77 @@ var (
 type Parser struct {
 	logger     *slog.Logger
 	httpClient *http.Client
+
+	keyring             openpgp.EntityList
+	keyringFunc         func(rawURL string) (openpgp.EntityList, error)
+	fetchEncryptionKey  bool
+	strictSignature     bool
+	lenient             bool
+	strictUnknownFields bool
+
+	maxBytes            int64
+	requireHTTPS        bool
+	allowedContentTypes []string
+	redirectPolicy      RedirectPolicy
+	maxRetryAfter       time.Duration
 }
 
 type ParserOptions struct {
 	Logger     *slog.Logger
 	HTTPClient *http.Client
+
+	// Keyring is used to verify the OpenPGP cleartext signature wrapping a
+	// "security.txt" file, when one is present.
+	Keyring openpgp.EntityList
+
+	// KeyringFunc, when set, is called to resolve the keyring to verify a
+	// signature against, receiving the URL the file was fetched from (empty
+	// when parsing from a reader). It takes precedence over Keyring.
+	KeyringFunc func(rawURL string) (openpgp.EntityList, error)
+
+	// FetchEncryptionKey, when true and no Keyring or KeyringFunc resolves a
+	// keyring, fetches the key referenced by the file's own Encryption field
+	// (when it is an "https://" URI) and verifies the signature against it.
+	FetchEncryptionKey bool
+
+	// StrictSignature causes Parse to fail if the input is not clearsigned or
+	// if the signature cannot be verified.
+	StrictSignature bool
+
+	// Lenient causes Parse to continue through the whole file instead of
+	// aborting on the first problem, returning every issue found combined
+	// into a single error via go-multierror. Use Lint instead for structured,
+	// line-numbered diagnostics.
+	Lenient bool
+
+	// StrictUnknownFields causes Parse to fail with an UnknownSymbolError on
+	// any field it doesn't recognize, instead of exposing it via TXT.Extensions
+	// as RFC9116 section 2.4 permits.
+	StrictUnknownFields bool
+
+	// MaxBytes caps how much of a fetched security.txt body ParseFromURL reads.
+	// Defaults to 128 KiB; the fetch fails with ErrResponseTooLarge if the body is larger.
+	MaxBytes int64
+
+	// RequireHTTPS causes ParseFromURL to reject non-"https://" URLs, including redirect targets.
+	RequireHTTPS bool
+
+	// AllowedContentTypes restricts which response Content-Type values
+	// ParseFromURL accepts, ignoring any "charset" parameter. Defaults to
+	// "text/plain". A response with a missing or empty Content-Type is
+	// rejected unless AllowedContentTypes explicitly includes "" - RFC9116
+	// section 3 expects servers to send "Content-Type: text/plain", so
+	// omitting it is treated as non-compliant rather than waived.
+	AllowedContentTypes []string
+
+	// RedirectPolicy controls how ParseFromURL handles HTTP redirects. Defaults to RedirectFollow.
+	RedirectPolicy RedirectPolicy
+
+	// MaxRetryAfter caps how long ParseFromURL will wait on a server-supplied
+	// Retry-After before retrying a 429 or 503 response. Defaults to 30s.
+	// The wait is always capped - a hostile or misconfigured Retry-After can
+	// never stall a caller past this ceiling.
+	MaxRetryAfter time.Duration
 }
 
 func NewParser() *Parser {
@@ -79,10 +191,25 @@ func NewParserWithOptions(opts ParserOptions) *Parser {
 	p := &Parser{}
 	if opts.Logger != nil {
 		p.logger = opts.Logger
+	} else {
+		p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
 	}
 	if opts.HTTPClient != nil {
 		p.httpClient = opts.HTTPClient
+	} else {
+		p.httpClient = http.DefaultClient
 	}
+	p.keyring = opts.Keyring
+	p.keyringFunc = opts.KeyringFunc
+	p.fetchEncryptionKey = opts.FetchEncryptionKey
+	p.strictSignature = opts.StrictSignature
+	p.lenient = opts.Lenient
+	p.strictUnknownFields = opts.StrictUnknownFields
+	p.maxBytes = opts.MaxBytes
+	p.requireHTTPS = opts.RequireHTTPS
+	p.allowedContentTypes = opts.AllowedContentTypes
+	p.redirectPolicy = opts.RedirectPolicy
+	p.maxRetryAfter = opts.MaxRetryAfter
 	return p
 }
 
@@ -92,136 +219,151 @@ func (p *Parser) SetLogger(logger *slog.Logger) *Parser {
 }
 
 // Parse parses a security.txt file.
-//
-//nolint:funlen,gocognit,cyclop
 func (p *Parser) Parse(in io.Reader) (*TXT, error) {
-	var (
-		txt     TXT
-		scanner = bufio.NewScanner(in)
-	)
+	return p.parse(context.Background(), in, "")
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
+// parse parses a security.txt file, optionally verifying the OpenPGP
+// cleartext signature wrapping it. sourceURL is the URL the file was
+// fetched from, if any, and is forwarded to KeyringFunc. ctx bounds any
+// fetch verification triggers, such as FetchEncryptionKey.
+func (p *Parser) parse(ctx context.Context, in io.Reader, sourceURL string) (*TXT, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file: %w", err)
+	}
 
-		if strings.HasPrefix(line, commentPrefix) || line == "" {
-			continue
+	var block *clearsign.Block
+	if bytes.HasPrefix(bytes.TrimSpace(data), clearsignHeader) {
+		block, _ = clearsign.Decode(data)
+		if block == nil {
+			return nil, ErrMalformedSignature
 		}
 
-		if strings.HasPrefix(line, acknowledgmentsPrefix) {
-			value := strings.TrimPrefix(line, acknowledgmentsPrefix)
-			value = strings.Trim(value, " ")
-
-			txt.Acknowledgments = append(txt.Acknowledgments, value)
+		data = block.Plaintext
+	} else if p.strictSignature {
+		return nil, ErrSignatureRequired
+	}
 
-			continue
-		}
+	txt, _, err := p.parseFields(data, p.lenient, p.strictUnknownFields)
+	if err != nil {
+		return nil, err
+	}
 
-		if strings.HasPrefix(line, canonicalPrefix) {
-			value := strings.TrimPrefix(line, canonicalPrefix)
-			value = strings.Trim(value, " ")
+	if block != nil {
+		txt.Signature = p.verifySignature(ctx, block, txt, sourceURL)
 
-			txt.Canonical = append(txt.Canonical, value)
+		if p.strictSignature && !txt.Signature.Verified {
+			if txt.Signature.Err != nil {
+				return nil, fmt.Errorf("signature verification failed: %w", txt.Signature.Err)
+			}
 
-			continue
+			return nil, ErrSignatureNotVerified
 		}
+	}
 
-		if strings.HasPrefix(line, contactPrefix) {
-			value := strings.TrimPrefix(line, contactPrefix)
-			value = strings.Trim(value, " ")
-
-			txt.Contact = append(txt.Contact, value)
-
-			continue
-		}
+	return txt, nil
+}
 
-		if strings.HasPrefix(line, encryptionPrefix) {
-			value := strings.TrimPrefix(line, encryptionPrefix)
-			value = strings.Trim(value, " ")
+// verifySignature verifies the OpenPGP cleartext signature wrapping a
+// security.txt file against the parser's configured keyring.
+func (p *Parser) verifySignature(ctx context.Context, block *clearsign.Block, txt *TXT, sourceURL string) *Signature {
+	sig := &Signature{}
 
-			txt.Encryption = value
+	keyring := p.keyring
 
-			continue
+	if p.keyringFunc != nil {
+		kr, err := p.keyringFunc(sourceURL)
+		if err != nil {
+			sig.Err = fmt.Errorf("unable to resolve keyring: %w", err)
+			return sig
 		}
 
-		if strings.HasPrefix(line, hiringPrefix) {
-			value := strings.TrimPrefix(line, hiringPrefix)
-			value = strings.Trim(value, " ")
-
-			txt.Hiring = value
+		keyring = kr
+	}
 
-			continue
+	if keyring == nil && p.fetchEncryptionKey {
+		kr, err := p.fetchEncryptionKeyring(ctx, txt.Encryption)
+		if err != nil {
+			sig.Err = fmt.Errorf("unable to fetch encryption key: %w", err)
+			return sig
 		}
 
-		if strings.HasPrefix(line, expiresPrefix) {
-			if !txt.Expires.IsZero() {
-				return nil, ErrExpiresMustBePresentOnlyOnce
-			}
-
-			value := strings.TrimPrefix(line, expiresPrefix)
-			value = strings.Trim(value, " ")
-
-			expires, err := time.Parse(time.RFC3339, value)
-			if err != nil {
-				return nil, ErrExpiresNotAValidRFC3339Date
-			}
+		keyring = kr
+	}
 
-			txt.Expires = expires
+	if keyring == nil {
+		sig.Err = ErrNoKeyringConfigured
+		return sig
+	}
 
-			continue
-		}
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body)
+	if err != nil {
+		sig.Err = err
+		return sig
+	}
 
-		if strings.HasPrefix(line, policyPrefix) {
-			value := strings.TrimPrefix(line, policyPrefix)
-			value = strings.Trim(value, " ")
+	sig.Verified = true
 
-			txt.Policy = value
+	if signer != nil {
+		sig.KeyID = fmt.Sprintf("%X", signer.PrimaryKey.KeyId)
 
-			continue
+		for name := range signer.Identities {
+			sig.Signer = name
+			break
 		}
+	}
 
-		if strings.HasPrefix(line, preferredLanguagesPrefix) {
-			if len(txt.PreferredLanguages) != 0 {
-				return nil, ErrPreferredLanguagesMustBePresentOnlyOnce
-			}
-
-			value := strings.TrimPrefix(line, preferredLanguagesPrefix)
-			value = strings.Trim(value, " ")
-
-			values := strings.Split(value, ",")
-			for _, value := range values {
-				txt.PreferredLanguages = append(txt.PreferredLanguages, strings.Trim(value, " "))
-			}
+	return sig
+}
 
-			continue
-		}
+// fetchEncryptionKeyring fetches the OpenPGP public key referenced by a
+// file's own Encryption field, for use as the verification keyring. encryption
+// comes from the untrusted file being parsed, so this goes through the same
+// redirect-aware client and MaxBytes cap as fetchFromURL rather than doing a
+// bare, unbounded Get.
+func (p *Parser) fetchEncryptionKeyring(ctx context.Context, encryption string) (openpgp.EntityList, error) {
+	if encryption == "" {
+		return nil, fmt.Errorf("no Encryption field present to fetch a key from")
+	}
 
-		return nil, &UnknownSymbolError{Line: line}
+	if !strings.HasPrefix(encryption, "https://") {
+		return nil, fmt.Errorf("Encryption field [%s] is not an https:// URL", encryption) //nolint:stylecheck
 	}
 
-	if len(txt.Contact) == 0 {
-		return nil, ErrContactMustBePresent
+	resp, err := p.get(ctx, p.redirectAwareClient(), encryption)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	if txt.Expires.IsZero() {
-		return nil, ErrExpiresMustBePresent
+	if resp.StatusCode > 299 {
+		return nil, &statusCodeError{statusCode: resp.StatusCode, url: encryption}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error while reading file: %w", err)
+	body, err := readLimited(resp.Body, p.maxBytes)
+	if err != nil {
+		return nil, err
 	}
 
-	return &txt, nil
+	return openpgp.ReadArmoredKeyRing(bytes.NewReader(body))
 }
 
+// ParseFromURL is equivalent to ParseFromURLContext with context.Background().
 func (p *Parser) ParseFromURL(rawURL string) (*TXT, error) {
+	return p.ParseFromURLContext(context.Background(), rawURL)
+}
 
+// ParseFromURLContext fetches and parses the security.txt file at rawURL. If
+// rawURL has an empty path, it also tries "security.txt" and
+// ".well-known/security.txt" appended to it, per RFC9116 section 3.
+func (p *Parser) ParseFromURLContext(ctx context.Context, rawURL string) (*TXT, error) {
 	url, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse provided URL [%s]: %w", rawURL, err)
 	}
 
-	res, err := p.parseFromURL(rawURL)
+	res, err := p.parseFromURL(ctx, rawURL)
 
 	if err == nil {
 		return res, nil
@@ -241,7 +383,7 @@ func (p *Parser) ParseFromURL(rawURL string) (*TXT, error) {
 
 			p.logger.Info("Trying URL", "url", newURL)
 
-			res, err := p.parseFromURL(newURL)
+			res, err := p.parseFromURL(ctx, newURL)
 
 			if err == nil {
 				return res, nil
@@ -256,18 +398,217 @@ func (p *Parser) ParseFromURL(rawURL string) (*TXT, error) {
 	return nil, multiErr.ErrorOrNil()
 }
 
-func (p *Parser) parseFromURL(url string) (*TXT, error) {
-	resp, err := p.httpClient.Get(url)
+func (p *Parser) parseFromURL(ctx context.Context, rawURL string) (*TXT, error) {
+	txt, _, err := p.fetchFromURL(ctx, rawURL)
+	return txt, err
+}
+
+// FetchContext performs the same hardened fetch and parse ParseFromURLContext
+// does, additionally returning the raw HTTP response (its body already
+// consumed) so callers that need metadata such as status code or TLS state -
+// like the scanner subpackage - don't have to bypass MaxBytes,
+// AllowedContentTypes, RequireHTTPS and RedirectPolicy with a fetch of their own.
+func (p *Parser) FetchContext(ctx context.Context, rawURL string) (*TXT, *http.Response, error) {
+	return p.fetchFromURL(ctx, rawURL)
+}
+
+func (p *Parser) fetchFromURL(ctx context.Context, rawURL string) (*TXT, *http.Response, error) {
+	body, finalURL, resp, err := p.fetchBody(ctx, rawURL)
 	if err != nil {
-		return nil, err
+		return nil, resp, err
+	}
+
+	txt, err := p.parse(ctx, bytes.NewReader(body), finalURL)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	txt.RetrievedFrom = rawURL
+	txt.FinalURL = finalURL
+
+	return txt, resp, nil
+}
+
+// fetchBody performs the hardened GET shared by fetchFromURL and
+// LintFromURLContext - RequireHTTPS, RedirectPolicy, Retry-After handling,
+// Content-Type checking and the MaxBytes cap - stopping short of parsing the
+// body, since the two callers interpret it differently (strict parse vs. lenient lint).
+func (p *Parser) fetchBody(ctx context.Context, rawURL string) (body []byte, finalURL string, resp *http.Response, err error) {
+	if p.requireHTTPS && !strings.HasPrefix(rawURL, "https://") {
+		return nil, "", nil, fmt.Errorf("%w: %s", ErrInsecureScheme, rawURL)
 	}
 
+	resp, err = p.getWithRetry(ctx, rawURL)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode > 299 {
-		return nil, &statusCodeError{
+		return nil, "", resp, &statusCodeError{
 			statusCode: resp.StatusCode,
-			url:        url,
+			url:        rawURL,
+		}
+	}
+
+	if err := checkContentType(resp.Header.Get("Content-Type"), p.allowedContentTypes); err != nil {
+		return nil, "", resp, err
+	}
+
+	body, err = readLimited(resp.Body, p.maxBytes)
+	if err != nil {
+		return nil, "", resp, err
+	}
+
+	finalURL = rawURL
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+
+	return body, finalURL, resp, nil
+}
+
+// getWithRetry fetches rawURL, honoring RedirectPolicy and RequireHTTPS for
+// every hop, and retries once after the delay a 429 or 503 response's
+// Retry-After header asks for, capped at MaxRetryAfter so a hostile or
+// misconfigured server can never stall the caller past that ceiling. The
+// wait aborts early if ctx is done.
+func (p *Parser) getWithRetry(ctx context.Context, rawURL string) (*http.Response, error) {
+	client := p.redirectAwareClient()
+
+	resp, err := p.get(ctx, client, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return resp, nil
+	}
+
+	wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	resp.Body.Close()
+
+	if !ok {
+		return p.get(ctx, client, rawURL)
+	}
+
+	maxWait := p.maxRetryAfter
+	if maxWait <= 0 {
+		maxWait = defaultMaxRetryAfter
+	}
+
+	if wait > maxWait {
+		wait = maxWait
+	}
+
+	if p.logger != nil {
+		p.logger.Info("Server asked to retry later", "url", rawURL, "status", resp.StatusCode, "retry-after", wait)
+	}
+
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return p.get(ctx, client, rawURL)
+}
+
+// get issues a single GET request bound to ctx, so cancellation and
+// deadlines apply to the request itself and to any redirects it follows.
+func (p *Parser) get(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Do(req)
+}
+
+// redirectAwareClient returns a copy of the parser's HTTP client with a
+// CheckRedirect hook enforcing RedirectPolicy and RequireHTTPS.
+func (p *Parser) redirectAwareClient() *http.Client {
+	base := p.httpClient
+	if base == nil {
+		base = http.DefaultClient
+	}
+
+	client := *base
+	client.CheckRedirect = p.checkRedirect
+
+	return &client
+}
+
+func (p *Parser) checkRedirect(req *http.Request, via []*http.Request) error {
+	if p.redirectPolicy == RedirectReject {
+		return http.ErrUseLastResponse
+	}
+
+	if p.requireHTTPS && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: redirected to %s", ErrInsecureScheme, req.URL)
+	}
+
+	if p.redirectPolicy == RedirectFollowSameHost && len(via) > 0 && req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("%w: redirected from %s to %s", ErrCrossHostRedirect, via[0].URL, req.URL)
+	}
+
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header value, given either as a
+// number of seconds or an HTTP-date.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when), true
+	}
+
+	return 0, false
+}
+
+// checkContentType verifies that contentType's media type (ignoring
+// parameters such as charset) is one of allowed, defaulting to "text/plain".
+func checkContentType(contentType string, allowed []string) error {
+	if len(allowed) == 0 {
+		allowed = defaultAllowedContentTypes
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	for _, a := range allowed {
+		if strings.EqualFold(mediaType, a) {
+			return nil
 		}
 	}
 
-	return p.Parse(resp.Body)
+	return fmt.Errorf("%w: [%s]", ErrDisallowedContentType, contentType)
+}
+
+// readLimited reads r, failing with ErrResponseTooLarge if it produces more than maxBytes.
+func readLimited(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file: %w", err)
+	}
+
+	if int64(len(data)) > maxBytes {
+		return nil, ErrResponseTooLarge
+	}
+
+	return data, nil
 }
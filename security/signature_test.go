@@ -0,0 +1,157 @@
+package security_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/security-txt-parser/security"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+)
+
+// testKey generates a small, fast-to-create OpenPGP entity for signing test fixtures.
+func testKey(t *testing.T) *openpgp.Entity {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Example Security Team", "", "security@example.com", &packet.Config{RSABits: 1024})
+	require.NoError(t, err)
+
+	return entity
+}
+
+func signedTXT(t *testing.T, key *openpgp.Entity) []byte {
+	t.Helper()
+
+	txt := &security.TXT{
+		Contact: []string{"mailto:security@example.com"},
+		Expires: time.Now().Add(24 * time.Hour).Truncate(time.Second),
+	}
+
+	data, err := security.MarshalWithOptions(txt, security.MarshalOptions{SigningKey: key})
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestSignatureRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t)
+	data := signedTXT(t, key)
+
+	require.True(t, strings.HasPrefix(string(data), "-----BEGIN PGP SIGNED MESSAGE-----"))
+
+	p := security.NewParserWithOptions(security.ParserOptions{Keyring: openpgp.EntityList{key}})
+
+	txt, err := p.Parse(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	require.NotNil(t, txt.Signature)
+	require.True(t, txt.Signature.Verified)
+	require.NoError(t, txt.Signature.Err)
+	require.Equal(t, "Example Security Team <security@example.com>", txt.Signature.Signer)
+	require.NotEmpty(t, txt.Signature.KeyID)
+}
+
+func TestStrictSignature(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t)
+	otherKey := testKey(t)
+	signed := signedTXT(t, key)
+	unsigned := "Contact: mailto:security@example.com\nExpires: 2099-01-01T00:00:00Z\n"
+
+	testCases := []struct {
+		name    string
+		opts    security.ParserOptions
+		body    string
+		wantErr error
+		verify  bool
+	}{
+		{
+			name:    "unsigned input is rejected",
+			opts:    security.ParserOptions{StrictSignature: true},
+			body:    unsigned,
+			wantErr: security.ErrSignatureRequired,
+		},
+		{
+			name:    "signed input with no keyring configured",
+			opts:    security.ParserOptions{StrictSignature: true},
+			body:    string(signed),
+			wantErr: security.ErrNoKeyringConfigured,
+		},
+		{
+			name: "signed input verified against the wrong key",
+			opts: security.ParserOptions{StrictSignature: true, Keyring: openpgp.EntityList{otherKey}},
+			body: string(signed),
+		},
+		{
+			name:   "signed input verified against the right key",
+			opts:   security.ParserOptions{StrictSignature: true, Keyring: openpgp.EntityList{key}},
+			body:   string(signed),
+			verify: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := security.NewParserWithOptions(testCase.opts)
+
+			txt, err := p.Parse(strings.NewReader(testCase.body))
+
+			if testCase.verify {
+				require.NoError(t, err)
+				require.True(t, txt.Signature.Verified)
+
+				return
+			}
+
+			require.Nil(t, txt)
+			require.Error(t, err)
+
+			if testCase.wantErr != nil {
+				require.True(t, errors.Is(err, testCase.wantErr), "expected error to wrap %v, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFetchEncryptionKeyHonorsMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 1024)))
+	}))
+	t.Cleanup(srv.Close)
+
+	key := testKey(t)
+
+	txt := &security.TXT{
+		Contact:    []string{"mailto:security@example.com"},
+		Expires:    time.Now().Add(24 * time.Hour).Truncate(time.Second),
+		Encryption: srv.URL + "/key.asc",
+	}
+
+	data, err := security.MarshalWithOptions(txt, security.MarshalOptions{SigningKey: key})
+	require.NoError(t, err)
+
+	p := security.NewParserWithOptions(security.ParserOptions{
+		StrictSignature:    true,
+		FetchEncryptionKey: true,
+		HTTPClient:         srv.Client(),
+		MaxBytes:           64,
+	})
+
+	_, err = p.Parse(strings.NewReader(string(data)))
+	require.Error(t, err)
+	require.True(t, errors.Is(err, security.ErrResponseTooLarge), "a too-large key response must be rejected, not read in full: %v", err)
+}
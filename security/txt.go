@@ -46,4 +46,44 @@ type TXT struct {
 	//
 	// This field MUST always be present and MUST NOT appear more than once.
 	Expires time.Time
+
+	// Signature holds the outcome of verifying the OpenPGP cleartext signature that
+	// RFC9116 section 2.3 recommends wrapping the file in.
+	//
+	// It is nil when the parsed file was not clearsigned, and on a TXT returned
+	// by Lint or LintFromURL, which strip the signature without verifying it.
+	Signature *Signature
+
+	// Extensions holds the IANA-registered or experimental ("x-"-prefixed) fields
+	// defined in RFC9116 section 2.4 that this package doesn't model directly.
+	//
+	// Keys are lower-cased field names; values are in the order they appeared in the file.
+	Extensions map[string][]string
+
+	// RetrievedFrom is the URL that was requested, before any redirects. When
+	// ParseFromURL falls back to a known path, it is that path's URL. Only set
+	// when the TXT was loaded from a URL (ParseFromURL, FetchContext or LintFromURL).
+	RetrievedFrom string
+
+	// FinalURL is the URL the security.txt file was actually fetched from,
+	// after following any redirects. Per RFC9116 section 2.5.3, it should
+	// appear in Canonical. Only set when the TXT was loaded from a URL.
+	FinalURL string
+}
+
+// Signature describes the result of verifying an OpenPGP cleartext signature
+// around a "security.txt" file.
+type Signature struct {
+	// Signer is the identity (e.g. "Example Security Team <security@example.com>")
+	// associated with the key that produced the signature, if it could be determined.
+	Signer string
+
+	// KeyID is the hex-encoded OpenPGP key ID that produced the signature.
+	KeyID string
+
+	// Verified reports whether the signature was successfully verified against the configured keyring.
+	Verified bool
+
+	// Err holds the error encountered while verifying the signature, if any.
+	Err error
 }
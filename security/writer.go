@@ -0,0 +1,128 @@
+package security
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+// MarshalOptions configures how Marshal and TXT.WriteTo serialize a TXT.
+type MarshalOptions struct {
+	// Header, when set, is emitted as a leading comment block, one "# " line per entry.
+	Header []string
+
+	// SigningKey, when set, wraps the output in an OpenPGP cleartext signature,
+	// as recommended by RFC9116 section 2.3.
+	SigningKey *openpgp.Entity
+}
+
+// Marshal serializes t into an RFC9116-compliant "security.txt" file.
+//
+// Repeatable fields are emitted one value per line, Preferred-Languages is
+// comma-joined, Expires is formatted per RFC3339, and fields are emitted in
+// the order used by the RFC9116 example file.
+func Marshal(t *TXT) ([]byte, error) {
+	return MarshalWithOptions(t, MarshalOptions{})
+}
+
+// MarshalWithOptions serializes t the same way Marshal does, with the given options applied.
+func MarshalWithOptions(t *TXT, opts MarshalOptions) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, line := range opts.Header {
+		fmt.Fprintf(&buf, "%s %s\n", commentPrefix, line)
+	}
+
+	writeField := func(prefix, value string) {
+		if value == "" {
+			return
+		}
+
+		fmt.Fprintf(&buf, "%s %s\n", prefix, value)
+	}
+
+	writeFields := func(prefix string, values []string) {
+		for _, value := range values {
+			writeField(prefix, value)
+		}
+	}
+
+	writeFields(contactPrefix, t.Contact)
+
+	if !t.Expires.IsZero() {
+		fmt.Fprintf(&buf, "%s %s\n", expiresPrefix, t.Expires.UTC().Format(time.RFC3339))
+	}
+
+	writeField(encryptionPrefix, t.Encryption)
+	writeFields(acknowledgmentsPrefix, t.Acknowledgments)
+
+	if len(t.PreferredLanguages) > 0 {
+		fmt.Fprintf(&buf, "%s %s\n", preferredLanguagesPrefix, strings.Join(t.PreferredLanguages, ", "))
+	}
+
+	writeFields(canonicalPrefix, t.Canonical)
+	writeField(policyPrefix, t.Policy)
+	writeField(hiringPrefix, t.Hiring)
+
+	extensionNames := make([]string, 0, len(t.Extensions))
+	for name := range t.Extensions {
+		extensionNames = append(extensionNames, name)
+	}
+
+	sort.Strings(extensionNames)
+
+	for _, name := range extensionNames {
+		writeFields(name+":", t.Extensions[name])
+	}
+
+	if opts.SigningKey == nil {
+		return buf.Bytes(), nil
+	}
+
+	return signClearsign(buf.Bytes(), opts.SigningKey)
+}
+
+// signClearsign wraps data in an OpenPGP cleartext signature produced with signingKey.
+func signClearsign(data []byte, signingKey *openpgp.Entity) ([]byte, error) {
+	var signed bytes.Buffer
+
+	plaintext, err := clearsign.Encode(&signed, signingKey.PrivateKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start clearsign encoding: %w", err)
+	}
+
+	if _, err := plaintext.Write(data); err != nil {
+		return nil, fmt.Errorf("unable to write signed content: %w", err)
+	}
+
+	if err := plaintext.Close(); err != nil {
+		return nil, fmt.Errorf("unable to finalize signature: %w", err)
+	}
+
+	return signed.Bytes(), nil
+}
+
+// WriteTo writes t as an RFC9116-compliant "security.txt" file to w.
+//
+// It implements io.WriterTo.
+func (t *TXT) WriteTo(w io.Writer) (int64, error) {
+	return t.WriteToWithOptions(w, MarshalOptions{})
+}
+
+// WriteToWithOptions writes t to w the same way WriteTo does, with the given options applied.
+func (t *TXT) WriteToWithOptions(w io.Writer, opts MarshalOptions) (int64, error) {
+	data, err := MarshalWithOptions(t, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(data)
+
+	return int64(n), err
+}